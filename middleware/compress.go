@@ -0,0 +1,99 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/geekip/mux"
+)
+
+// compressWriter lazily wraps a ResponseWriter in a gzip or deflate writer
+// once the handler's Content-Type is known, so Compress can honor types
+type compressWriter struct {
+	http.ResponseWriter
+	level    int
+	encoding string
+	types    map[string]bool
+	writer   io.WriteCloser
+	decided  bool
+	active   bool
+}
+
+func (cw *compressWriter) decide() {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+	contentType := strings.SplitN(cw.Header().Get("Content-Type"), ";", 2)[0]
+	if len(cw.types) > 0 && !cw.types[contentType] {
+		return
+	}
+	switch cw.encoding {
+	case "gzip":
+		cw.writer, _ = gzip.NewWriterLevel(cw.ResponseWriter, cw.level)
+	case "deflate":
+		cw.writer, _ = flate.NewWriter(cw.ResponseWriter, cw.level)
+	}
+	if cw.writer != nil {
+		cw.active = true
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+	}
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	cw.decide()
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	cw.decide()
+	if cw.active {
+		return cw.writer.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *compressWriter) Close() error {
+	if cw.active {
+		return cw.writer.Close()
+	}
+	return nil
+}
+
+// Compress gzip- or deflate-encodes responses at the given compression level
+// based on the request's Accept-Encoding header, restricted to the given
+// Content-Type values (or every type if none are given)
+func Compress(level int, types ...string) mux.Middleware {
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accept := r.Header.Get("Accept-Encoding")
+			var encoding string
+			switch {
+			case strings.Contains(accept, "gzip"):
+				encoding = "gzip"
+			case strings.Contains(accept, "deflate"):
+				encoding = "deflate"
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, level: level, encoding: encoding, types: allowed}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/geekip/mux"
+)
+
+// responseLogger wraps http.ResponseWriter to capture the status code and
+// byte count written by the handler, for Logger
+type responseLogger struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rl *responseLogger) WriteHeader(status int) {
+	rl.status = status
+	rl.ResponseWriter.WriteHeader(status)
+}
+
+func (rl *responseLogger) Write(b []byte) (int, error) {
+	n, err := rl.ResponseWriter.Write(b)
+	rl.bytes += n
+	return n, err
+}
+
+// Logger logs one line per request: method, path, status, bytes written,
+// and duration
+func Logger() mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rl := &responseLogger{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rl, r)
+			log.Printf("%s %s %d %dB %s", r.Method, r.URL.Path, rl.status, rl.bytes, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = RequestIDFromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen == "" || w.Header().Get("X-Request-ID") != seen {
+		t.Fatalf("got context id %q header %q, want matching non-empty values", seen, w.Header().Get("X-Request-ID"))
+	}
+}
+
+func TestRecovererCallsOnPanic(t *testing.T) {
+	var gotErr interface{}
+	onPanic := func(w http.ResponseWriter, r *http.Request, err interface{}) {
+		gotErr = err
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	h := Recoverer(onPanic)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if gotErr != "boom" {
+		t.Fatalf("got onPanic err %v, want \"boom\"", gotErr)
+	}
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("got status %d, want the custom onPanic's 418", w.Code)
+	}
+}
+
+func TestRecovererDefaultsToInternalServerError(t *testing.T) {
+	h := Recoverer(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want 500 with a nil onPanic", w.Code)
+	}
+}
+
+func TestStripSlashes(t *testing.T) {
+	var seenPath string
+	h := StripSlashes()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenPath = r.URL.Path
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/foo/", nil))
+	if seenPath != "/foo" {
+		t.Fatalf("got path %q, want trailing slash stripped to \"/foo\"", seenPath)
+	}
+}
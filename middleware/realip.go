@@ -0,0 +1,63 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/geekip/mux"
+)
+
+// RealIP rewrites r.RemoteAddr from X-Forwarded-For or X-Real-IP, but only
+// when the immediate peer is one of the trusted proxy CIDRs (or IPs). With
+// no trusted proxies, every peer is treated as trusted.
+func RealIP(trusted ...string) mux.Middleware {
+	nets := make([]*net.IPNet, 0, len(trusted))
+	for _, cidr := range trusted {
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+			nets = append(nets, ipnet)
+		}
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		if len(nets) == 0 {
+			return true
+		}
+		for _, ipnet := range nets {
+			if ipnet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			if peer := net.ParseIP(host); peer != nil && isTrusted(peer) {
+				if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+					if ip := strings.TrimSpace(strings.Split(fwd, ",")[0]); ip != "" {
+						r.RemoteAddr = ip
+					}
+				} else if real := r.Header.Get("X-Real-IP"); real != "" {
+					r.RemoteAddr = real
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/geekip/mux"
+)
+
+// Recoverer recovers from panics in the handler chain, logs the panic value
+// and a stack trace, and reports the panic via onPanic, which has the same
+// signature as Mux's InternalErrorHandler so it can be wired straight
+// through. A nil onPanic falls back to a plain 500 Internal Server Error.
+func Recoverer(onPanic func(http.ResponseWriter, *http.Request, interface{})) mux.Middleware {
+	if onPanic == nil {
+		onPanic = func(w http.ResponseWriter, r *http.Request, err interface{}) {
+			http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					log.Printf("panic: %v\n%s", err, debug.Stack())
+					onPanic(w, r, err)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/geekip/mux"
+)
+
+type ctxKeyRequestID int
+
+const requestIDKey ctxKeyRequestID = 0
+
+var requestIDCounter uint64
+
+// RequestID attaches a monotonically unique ID to the request context and
+// the X-Request-ID response header
+func RequestID() mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := strconv.FormatUint(atomic.AddUint64(&requestIDCounter, 1), 10)
+			w.Header().Set("X-Request-ID", id)
+			ctx := context.WithValue(r.Context(), requestIDKey, id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID attached by RequestID, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
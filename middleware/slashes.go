@@ -0,0 +1,41 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/geekip/mux"
+)
+
+// StripSlashes removes a trailing slash from the request path (except the
+// root "/") before passing the request on, so "/foo/" and "/foo" route alike
+func StripSlashes() mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.Path) > 1 && strings.HasSuffix(r.URL.Path, "/") {
+				r.URL.Path = strings.TrimSuffix(r.URL.Path, "/")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RedirectSlashes redirects requests with a trailing slash (except the root
+// "/") to the same path without it, preserving the query string
+func RedirectSlashes() mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.Path) > 1 && strings.HasSuffix(r.URL.Path, "/") {
+				u := *r.URL
+				u.Path = strings.TrimSuffix(u.Path, "/")
+				http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/geekip/mux"
+)
+
+// Throttle limits the number of in-flight requests to n, responding 503 to
+// requests beyond that limit instead of queueing them
+func Throttle(n int) mux.Middleware {
+	sem := make(chan struct{}, n)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				next.ServeHTTP(w, r)
+			default:
+				http.Error(w, "503 service unavailable", http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/geekip/mux"
+)
+
+// Timeout cancels the request context after d, so context-aware handlers
+// and downstream calls can abort once the deadline passes
+func Timeout(d time.Duration) mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
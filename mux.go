@@ -5,9 +5,13 @@
 package mux
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"path"
+	"sort"
 	"strings"
 )
 
@@ -15,20 +19,29 @@ type (
 	Middleware func(http.Handler) http.Handler
 	Mux        struct {
 		prefix                  string
+		host                    string
 		methods                 []string
 		node                    *node
+		hostNode                *node
+		names                   map[string]*node
+		lastNode                *node
+		lastRoutes              []*route
 		middlewares             []Middleware
 		notFoundHandler         http.HandlerFunc
 		methodNotAllowedHandler http.HandlerFunc
 		internalErrorHandler    func(http.ResponseWriter, *http.Request, interface{})
 		panicHandler            func(error)
+		handleMethodNotAllowed  bool
+		handleOPTIONS           bool
 	}
 )
 
 // New creates and initializes a new Mux instance with default error handlers
 func New() *Mux {
 	return &Mux{
-		node: newNode(),
+		node:     newNode(),
+		hostNode: newNode(),
+		names:    make(map[string]*node),
 		notFoundHandler: func(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "404 page not found", http.StatusNotFound)
 		},
@@ -38,7 +51,9 @@ func New() *Mux {
 		internalErrorHandler: func(w http.ResponseWriter, r *http.Request, err interface{}) {
 			http.Error(w, "500 internal server error", http.StatusInternalServerError)
 		},
-		panicHandler: func(err error) { panic(err) },
+		panicHandler:           func(err error) { panic(err) },
+		handleMethodNotAllowed: true,
+		handleOPTIONS:          true,
 	}
 }
 
@@ -56,6 +71,8 @@ func (m *Mux) Group(pattern string) *Mux {
 	return &Mux{
 		prefix:      pathJoin(m.prefix, pattern),
 		node:        m.node,
+		hostNode:    m.hostNode,
+		names:       m.names,
 		middlewares: m.middlewares,
 	}
 }
@@ -69,28 +86,294 @@ func (m *Mux) Method(methods ...string) *Mux {
 	return m
 }
 
+// Host specifies a host pattern (e.g. "api.{sub}.example.com") that the
+// subsequent route registration must match, in addition to its path
+func (m *Mux) Host(pattern string) *Mux {
+	if pattern == "" {
+		m.panicHandler(errors.New("mux unkown host pattern"))
+	}
+	m.host = pattern
+	return m
+}
+
 // Handle registers a route with the given pattern and handler
 func (m *Mux) Handle(pattern string, handler http.Handler) *Mux {
 	fullPattern := pathJoin(m.prefix, pattern)
 	if len(m.methods) == 0 {
 		m.methods = append(m.methods, "*")
 	}
+
+	root := m.node
+	if m.host != "" {
+		hostRoot, err := m.hostNode.addHost(m.host)
+		if err != nil {
+			m.panicHandler(err)
+		}
+		root = hostRoot
+	}
+
+	var terminal *node
+	var routes []*route
 	for _, method := range m.methods {
 		method = strings.ToUpper(method)
-		_, err := m.node.add(method, fullPattern, handler, m.middlewares)
+		n, rt, err := root.add(method, fullPattern, handler, m.middlewares)
 		if err != nil {
 			m.panicHandler(err)
 		}
+		terminal = n
+		routes = append(routes, rt)
 	}
+	terminal.pattern = fullPattern
+	terminal.hostPattern = m.host
+	m.lastNode = terminal
+	m.lastRoutes = routes
+
 	m.methods = nil
+	m.host = ""
+	return m
+}
+
+// Queries attaches a query-string predicate to the most recently registered
+// route(s), requiring each key to be present and its value to match its
+// template ("literal" or "{name}"/"{name:regex}", the latter contributing to
+// Params). A missing key never matches, even against a bare "{name}".
+func (m *Mux) Queries(pairs ...string) *Mux {
+	return m.addPredicate(pairs, func(r *http.Request) presenceGetter {
+		return queryValues(r.URL.Query())
+	})
+}
+
+// Headers attaches a header predicate to the most recently registered
+// route(s), matching pairs the same way Queries does
+func (m *Mux) Headers(pairs ...string) *Mux {
+	return m.addPredicate(pairs, func(r *http.Request) presenceGetter {
+		return headerValues(r.Header)
+	})
+}
+
+// Schemes attaches a scheme predicate to the most recently registered
+// route(s), matching against r.URL.Scheme (falling back to https when
+// r.TLS is set, http otherwise)
+func (m *Mux) Schemes(schemes ...string) *Mux {
+	if len(schemes) == 0 || len(m.lastRoutes) == 0 {
+		m.panicHandler(errors.New("mux unkown scheme"))
+		return m
+	}
+	allowed := make(map[string]bool, len(schemes))
+	for _, s := range schemes {
+		allowed[strings.ToLower(s)] = true
+	}
+	return m.addPredicateFunc(func(r *http.Request) (map[string]string, bool) {
+		return nil, allowed[requestScheme(r)]
+	})
+}
+
+// presenceGetter abstracts url.Values and http.Header, reporting both a
+// key's value and whether it was present at all, since Get alone can't tell
+// a missing key from one present with an empty value
+type presenceGetter interface {
+	Lookup(string) (string, bool)
+}
+
+// queryValues adapts url.Values to presenceGetter
+type queryValues url.Values
+
+func (v queryValues) Lookup(key string) (string, bool) {
+	vs, ok := v[key]
+	if !ok || len(vs) == 0 {
+		return "", false
+	}
+	return vs[0], true
+}
+
+// headerValues adapts http.Header to presenceGetter, matching Header.Get's
+// canonicalization so "x-api-version" and "X-Api-Version" behave alike
+type headerValues http.Header
+
+func (h headerValues) Lookup(key string) (string, bool) {
+	vs, ok := h[http.CanonicalHeaderKey(key)]
+	if !ok || len(vs) == 0 {
+		return "", false
+	}
+	return vs[0], true
+}
+
+// addPredicate builds a predicate from alternating key/template pairs and
+// attaches it to the most recently registered route(s)
+func (m *Mux) addPredicate(pairs []string, getter func(*http.Request) presenceGetter) *Mux {
+	if len(pairs)%2 != 0 || len(m.lastRoutes) == 0 {
+		m.panicHandler(errors.New("mux unkown predicate pairs"))
+		return m
+	}
+	type pair struct{ key, template string }
+	pairs2 := make([]pair, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		pairs2 = append(pairs2, pair{pairs[i], pairs[i+1]})
+	}
+	return m.addPredicateFunc(func(r *http.Request) (map[string]string, bool) {
+		values := getter(r)
+		params := make(map[string]string)
+		for _, p := range pairs2 {
+			actual, present := values.Lookup(p.key)
+			if !present {
+				return nil, false
+			}
+			name, value, ok := matchTemplate(p.template, actual)
+			if !ok {
+				return nil, false
+			}
+			if name != "" {
+				params[name] = value
+			}
+		}
+		return params, true
+	})
+}
+
+// addPredicateFunc attaches p to every route registered by the last Handle call
+func (m *Mux) addPredicateFunc(p predicate) *Mux {
+	for _, rt := range m.lastRoutes {
+		rt.predicates = append(rt.predicates, p)
+	}
+	return m
+}
+
+// requestScheme reports the request's scheme, preferring r.URL.Scheme (set
+// by proxies) and falling back to TLS detection
+func requestScheme(r *http.Request) string {
+	if scheme := r.URL.Scheme; scheme != "" {
+		return strings.ToLower(scheme)
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Name tags the most recently registered route with a name, so it can later
+// be reconstructed with URL, URLPath or URLHost
+func (m *Mux) Name(name string) *Mux {
+	if name == "" || m.lastNode == nil {
+		m.panicHandler(errors.New("mux unkown route name"))
+		return m
+	}
+	m.names[name] = m.lastNode
 	return m
 }
 
+// URL reconstructs a URL for the named route, combining its host (if any)
+// and path by substituting {param} and {param:regex} placeholders in the
+// stored pattern with the supplied key/value pairs
+func (m *Mux) URL(name string, pairs ...string) (*url.URL, error) {
+	path, err := m.URLPath(name, pairs...)
+	if err != nil {
+		return nil, err
+	}
+	host, err := m.URLHost(name, pairs...)
+	if err != nil {
+		return nil, err
+	}
+	u := &url.URL{Path: path}
+	if host != "" {
+		u.Scheme = "http"
+		u.Host = host
+	}
+	return u, nil
+}
+
+// URLPath reconstructs the path portion of a named route
+func (m *Mux) URLPath(name string, pairs ...string) (string, error) {
+	n, ok := m.names[name]
+	if !ok {
+		return "", fmt.Errorf("mux: no route named %q", name)
+	}
+	return buildURL(n.pattern, "/", pairs...)
+}
+
+// URLHost reconstructs the host portion of a named route. It returns an
+// empty string if the route was not registered with Host
+func (m *Mux) URLHost(name string, pairs ...string) (string, error) {
+	n, ok := m.names[name]
+	if !ok {
+		return "", fmt.Errorf("mux: no route named %q", name)
+	}
+	if n.hostPattern == "" {
+		return "", nil
+	}
+	return buildURL(n.hostPattern, ".", pairs...)
+}
+
+// buildURL substitutes {name} and {name:regex} placeholders in pattern,
+// split on sep, with the values supplied as alternating key/value pairs.
+// Wildcard placeholders ({*name}) are substituted like any other, requiring
+// a single value that already represents the joined remainder.
+func buildURL(pattern, sep string, pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", errors.New("mux: URL pairs must be key-value")
+	}
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+
+	segments := strings.Split(pattern, sep)
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, prefixParam) || !strings.HasSuffix(segment, suffixParam) {
+			continue
+		}
+		param := segment[1 : len(segment)-1]
+		parts := strings.SplitN(param, prefixRegexp, 2)
+		paramName := parts[0]
+
+		value, ok := values[paramName]
+		if !ok {
+			return "", fmt.Errorf("mux: missing value for %q", paramName)
+		}
+		if len(parts) > 1 && !makeRegexp(parts[1]).MatchString(value) {
+			return "", fmt.Errorf("mux: value %q does not match %q", value, segment)
+		}
+		segments[i] = value
+	}
+	return strings.Join(segments, sep), nil
+}
+
 // HandlerFunc registers a route with the given pattern and handler function
 func (m *Mux) HandlerFunc(pattern string, handler http.HandlerFunc) *Mux {
 	return m.Handle(pattern, http.HandlerFunc(handler))
 }
 
+// Mount registers handler to serve every request whose path begins with
+// prefix, stripping prefix from the request path before delegating. Unlike
+// Group, the mounted handler owns its own routing entirely, so a separate
+// *Mux, an http.FileServer, or any other http.Handler can be mounted without
+// its patterns conflicting with this Mux's trie. Middleware registered
+// before Mount still wraps the sub-tree.
+func (m *Mux) Mount(prefix string, handler http.Handler) *Mux {
+	mounted := strings.TrimSuffix(pathJoin(m.prefix, prefix), "/")
+	if mounted == "" {
+		mounted = "/"
+	}
+
+	forward := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subPath := Params(r)["*mountpath"]
+		if !strings.HasPrefix(subPath, "/") {
+			subPath = "/" + subPath
+		}
+		// Clear this mux's routing params/route before delegating, so the
+		// mounted handler's own routing starts from a clean Params map
+		// instead of observing *mountpath.
+		ctx := context.WithValue(r.Context(), keyParam, nil)
+		ctx = context.WithValue(ctx, keyRoute, nil)
+		r2 := r.Clone(ctx)
+		r2.URL.Path = subPath
+		handler.ServeHTTP(w, r2)
+	})
+
+	m.Handle(mounted, forward)
+	m.Handle(pathJoin(mounted, "/{*mountpath}"), forward)
+	return m
+}
+
 // ServeHTTP implements the http.Handler interface to handle incoming requests
 func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer func() {
@@ -99,20 +382,129 @@ func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
+	matched := m.findHost(r)
+	if matched == nil {
+		matched = m.node.find(r)
+	}
+
 	var handler http.Handler
-	node := m.node.find(r.Method, r.URL.Path)
-	if node == nil {
+	switch {
+	case matched == nil:
 		handler = m.notFoundHandler
-	} else {
-		handler = node.handler
-		if handler == nil {
+	case matched.handler != nil:
+		handler = matched.handler
+		r = matched.withContext(r)
+	default:
+		r = matched.withContext(r)
+		if allow := m.allowHeader(matched); allow != "" {
+			w.Header().Set("Allow", allow)
+		}
+		switch {
+		case m.handleOPTIONS && r.Method == http.MethodOptions:
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+		case m.handleMethodNotAllowed:
 			handler = m.methodNotAllowedHandler
+		default:
+			handler = m.notFoundHandler
 		}
-		r = node.withContext(r)
 	}
 	handler.ServeHTTP(w, r)
 }
 
+// allowHeader builds the sorted, deduped Allow header value for n's
+// registered methods, adding OPTIONS automatically unless the caller opted
+// out with HandleOPTIONS(false)
+func (m *Mux) allowHeader(n *node) string {
+	methods := n.allowedMethods()
+	if m.handleOPTIONS {
+		hasOptions := false
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				hasOptions = true
+				break
+			}
+		}
+		if !hasOptions {
+			methods = append(methods, http.MethodOptions)
+			sort.Strings(methods)
+		}
+	}
+	return strings.Join(methods, ", ")
+}
+
+// HandleMethodNotAllowed toggles whether a path match with no handler for
+// the request method responds 405 (default) or falls back to 404
+func (m *Mux) HandleMethodNotAllowed(enable bool) *Mux {
+	m.handleMethodNotAllowed = enable
+	return m
+}
+
+// HandleOPTIONS toggles automatic 204 responses (with an Allow header) to
+// OPTIONS requests that have no explicitly registered OPTIONS handler
+func (m *Mux) HandleOPTIONS(enable bool) *Mux {
+	m.handleOPTIONS = enable
+	return m
+}
+
+// findHost matches the request's host (port stripped) against the host-trie
+// and, if a host matches, resolves the path within that host's routes,
+// merging captured host params with any path params. Returns nil if no
+// host-scoped route registered under this Mux matches the request.
+func (m *Mux) findHost(r *http.Request) *node {
+	if m.hostNode == nil {
+		return nil
+	}
+	host := r.Host
+	if i := strings.IndexByte(host, ':'); i != -1 {
+		host = host[:i]
+	}
+	hostRoot, hostParams := m.hostNode.findHost(host)
+	if hostRoot == nil {
+		return nil
+	}
+	node := hostRoot.findParams(r, hostParams)
+	return node
+}
+
+// Walk performs a depth-first traversal of the routing trie, invoking fn for
+// every registered (method, pattern) pair. Routes registered with Host are
+// reported as "host/path" so they aren't silently skipped
+func (m *Mux) Walk(fn func(method, pattern string, handler http.Handler, middlewares []Middleware) error) error {
+	if err := m.node.walk("", fn); err != nil {
+		return err
+	}
+	if m.hostNode != nil {
+		return m.hostNode.walkHost("", fn)
+	}
+	return nil
+}
+
+// RouteInfo is a snapshot of one registered (method, pattern) route
+type RouteInfo struct {
+	Method      string
+	Pattern     string
+	Handler     http.Handler
+	Middlewares []Middleware
+}
+
+// Routes returns a snapshot of every registered route, for building route
+// tables, OpenAPI specs, or admin dashboards
+func (m *Mux) Routes() []RouteInfo {
+	var routes []RouteInfo
+	m.Walk(func(method, pattern string, handler http.Handler, middlewares []Middleware) error {
+		routes = append(routes, RouteInfo{
+			Method:      method,
+			Pattern:     pattern,
+			Handler:     handler,
+			Middlewares: middlewares,
+		})
+		return nil
+	})
+	return routes
+}
+
 // NotFoundHandler sets a custom handler for 404 Not Found responses
 func (m *Mux) NotFoundHandler(handler http.HandlerFunc) *Mux {
 	m.notFoundHandler = handler
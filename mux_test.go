@@ -0,0 +1,264 @@
+// Copyright 2024 Geekip. All rights reserved.
+// Use of this source code is governed by a MIT style.
+// at https://github.com/geekip/mux
+
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestRequest(method, target string) *http.Request {
+	return httptest.NewRequest(method, target, nil)
+}
+
+func TestHostMatching(t *testing.T) {
+	m := New()
+	m.Host("api.{sub}.example.com").HandlerFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		params := Params(r)
+		w.Write([]byte(params["sub"] + ":" + params["id"]))
+	})
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "http://api.v1.example.com:8080/users/42")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "v1:42" {
+		t.Fatalf("got status %d body %q, want 200 \"v1:42\" (wildcard host + port stripping)", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodGet, "http://not-matching.example.org/users/42")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want 404 for non-matching host", w.Code)
+	}
+}
+
+func TestHostExtensionNoCollision(t *testing.T) {
+	m := New()
+	m.Host("example.com").HandlerFunc("/uk/b", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("A"))
+	})
+	m.Host("example.com.uk").HandlerFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("B"))
+	})
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "http://example.com/uk/b")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "A" {
+		t.Fatalf("got status %d body %q, want 200 \"A\": example.com's own /uk/b route, not example.com.uk's /b route", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodGet, "http://example.com.uk/b")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "B" {
+		t.Fatalf("got status %d body %q, want 200 \"B\" for example.com.uk/b", w.Code, w.Body.String())
+	}
+}
+
+func TestHostMatchingWithGroup(t *testing.T) {
+	root := New()
+	admin := root.Group("/admin")
+	admin.Host("admin.example.com").HandlerFunc("/dash", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("dash"))
+	})
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "http://admin.example.com/admin/dash")
+	root.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "dash" {
+		t.Fatalf("got status %d body %q, want 200 \"dash\" serving a Group's host route through the root Mux", w.Code, w.Body.String())
+	}
+}
+
+func TestNamedRoutesURL(t *testing.T) {
+	m := New()
+	m.Host("{sub}.example.com").HandlerFunc("/users/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {}).Name("user")
+
+	u, err := m.URL("user", "sub", "api", "id", "42")
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	if u.Path != "/users/42" || u.Host != "api.example.com" {
+		t.Fatalf("got path %q host %q, want /users/42 api.example.com", u.Path, u.Host)
+	}
+
+	if _, err := m.URL("user", "sub", "api", "id", "notanumber"); err == nil {
+		t.Fatal("want error substituting a value that fails its {id:[0-9]+} regex")
+	}
+
+	if _, err := m.URLPath("missing"); err == nil {
+		t.Fatal("want error looking up an unregistered route name")
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	m := New()
+	m.HandlerFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(Params(r)["ver"]))
+	}).Headers("X-Api-Version", "{ver}").Queries("q", "{q}")
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "/search?q=golang")
+	r.Header.Set("X-Api-Version", "v2")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "v2" {
+		t.Fatalf("got status %d body %q, want 200 \"v2\" when header and query are present", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodGet, "/search?q=golang")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want 404 when X-Api-Version header is absent (bare {ver} must not match a missing key)", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodGet, "/search")
+	r.Header.Set("X-Api-Version", "v2")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want 404 when q query param is absent", w.Code)
+	}
+}
+
+func TestSchemes(t *testing.T) {
+	m := New()
+	m.HandlerFunc("/secure", func(w http.ResponseWriter, r *http.Request) {}).Schemes("https")
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "https://example.com/secure")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200 for an https request", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodGet, "http://example.com/secure")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want 404 for an http request against a https-only route", w.Code)
+	}
+}
+
+func TestWalkAndRoutes(t *testing.T) {
+	m := New()
+	m.HandlerFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	m.Host("api.example.com").HandlerFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	routes := m.Routes()
+	seen := make(map[string]bool)
+	for _, rt := range routes {
+		seen[rt.Pattern] = true
+	}
+	if !seen["/users/{id}"] {
+		t.Fatalf("Routes() missing plain path route, got %+v", routes)
+	}
+	if !seen["api.example.com/users/{id}"] {
+		t.Fatalf("Routes() missing host-scoped route as \"host/path\", got %+v", routes)
+	}
+	for pattern := range seen {
+		if pattern != "/users/{id}" && pattern != "api.example.com/users/{id}" {
+			t.Fatalf("Routes() produced an unexpected entry %q (host-trie walked past its host-root)", pattern)
+		}
+	}
+}
+
+func TestWalkHostExtension(t *testing.T) {
+	m := New()
+	m.Host("example.com").HandlerFunc("/uk/b", func(w http.ResponseWriter, r *http.Request) {})
+	m.Host("example.com.uk").HandlerFunc("/b", func(w http.ResponseWriter, r *http.Request) {})
+
+	seen := make(map[string]bool)
+	for _, rt := range m.Routes() {
+		seen[rt.Pattern] = true
+	}
+	if !seen["example.com/uk/b"] {
+		t.Fatalf("Routes() missing example.com's own route, got %v", seen)
+	}
+	if !seen["example.com.uk/b"] {
+		t.Fatalf("Routes() missing example.com.uk/b (host label \"uk\" rendered as a path segment instead), got %v", seen)
+	}
+}
+
+func TestMount(t *testing.T) {
+	sub := New()
+	sub.HandlerFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong:" + r.URL.Path))
+	})
+
+	root := New()
+	root.Mount("/api", sub)
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "/api/ping")
+	root.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "pong:/ping" {
+		t.Fatalf("got status %d body %q, want 200 \"pong:/ping\"", w.Code, w.Body.String())
+	}
+}
+
+func TestMountClearsOuterParams(t *testing.T) {
+	sub := New()
+	sub.HandlerFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		if params := Params(r); len(params) != 0 {
+			t.Errorf("got params %v inside the mounted sub-mux, want none (the outer *mountpath must not leak in)", params)
+		}
+		w.Write([]byte("pong"))
+	})
+
+	root := New()
+	root.Mount("/api", sub)
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodGet, "/api/ping")
+	root.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", w.Code)
+	}
+}
+
+func TestAllowHeaderAndOptions(t *testing.T) {
+	m := New()
+	m.Method("GET", "POST").HandlerFunc("/items", func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	r := newTestRequest(http.MethodDelete, "/items")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want 405", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, OPTIONS, POST" {
+		t.Fatalf("got Allow %q, want sorted \"GET, OPTIONS, POST\"", allow)
+	}
+
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodOptions, "/items")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want 204 for auto-handled OPTIONS", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, OPTIONS, POST" {
+		t.Fatalf("got Allow %q on OPTIONS response, want \"GET, OPTIONS, POST\"", allow)
+	}
+
+	m.HandleOPTIONS(false)
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodOptions, "/items")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want 405 once HandleOPTIONS(false) disables auto-OPTIONS", w.Code)
+	}
+
+	m.HandleMethodNotAllowed(false)
+	w = httptest.NewRecorder()
+	r = newTestRequest(http.MethodDelete, "/items")
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want 404 once HandleMethodNotAllowed(false) falls back to not-found", w.Code)
+	}
+}
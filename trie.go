@@ -10,22 +10,39 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 )
 
 type (
 	ctxKey int
-	node   struct {
+	// predicate is an additional request constraint (query, header, scheme)
+	// attached to a route. It reports whether r satisfies the constraint and
+	// any params it captures (e.g. from a {name:regex} value template).
+	predicate func(r *http.Request) (map[string]string, bool)
+	// route is one candidate handler registered for a method at a node.
+	// Multiple routes may share the same method and pattern, distinguished
+	// by their predicates; find tries them in registration order.
+	route struct {
 		handler     http.Handler
 		middlewares []Middleware
-		methods     map[string]http.Handler
+		predicates  []predicate
+	}
+	node struct {
+		handler     http.Handler
+		methods     map[string][]*route
 		children    map[string]*node
 		params      map[string]string
 		paramName   string
 		paramNode   *node
 		regex       *regexp.Regexp
+		regexSrc    string
 		isEnd       bool
+		isHostRoot  bool
+		pathRoot    *node
+		pattern     string
+		hostPattern string
 	}
 	reMaps map[string]*regexp.Regexp
 )
@@ -58,18 +75,50 @@ func makeRegexp(pattern string) *regexp.Regexp {
 func newNode() *node {
 	return &node{
 		children: make(map[string]*node),
-		methods:  make(map[string]http.Handler),
+		methods:  make(map[string][]*route),
 		params:   make(map[string]string),
 	}
 }
 
-// add registers a route handler for the given method and pattern
-// Returns error for invalid inputs or route conflicts
-func (n *node) add(method, pattern string, handler http.Handler, middlewares []Middleware) (*node, error) {
-	if method == "" || pattern == "" || handler == nil {
-		return nil, errors.New("mux handle error")
+// match reports whether r satisfies every predicate on the route, returning
+// the params captured along the way. A route with no predicates always matches.
+func (rt *route) match(r *http.Request) (map[string]string, bool) {
+	if len(rt.predicates) == 0 {
+		return nil, true
+	}
+	params := make(map[string]string)
+	for _, p := range rt.predicates {
+		extra, ok := p(r)
+		if !ok {
+			return nil, false
+		}
+		for k, v := range extra {
+			params[k] = v
+		}
+	}
+	return params, true
+}
+
+// matchTemplate matches actual against a literal string or a {name}/{name:regex}
+// placeholder, returning any captured param name and whether it matched.
+func matchTemplate(template, actual string) (paramName, value string, ok bool) {
+	if strings.HasPrefix(template, prefixParam) && strings.HasSuffix(template, suffixParam) {
+		param := template[1 : len(template)-1]
+		parts := strings.SplitN(param, prefixRegexp, 2)
+		name := parts[0]
+		if len(parts) > 1 && !makeRegexp(parts[1]).MatchString(actual) {
+			return "", "", false
+		}
+		return name, actual, true
 	}
-	segments := strings.Split(pattern, "/")
+	return "", actual, template == actual
+}
+
+// descend walks pattern segments through the trie, creating static and
+// parameter nodes as needed, and returns the node reached after the last
+// segment. Wildcard segments ({*name}) are only permitted when allowWildcard
+// is true, since host patterns have no equivalent to a trailing path capture.
+func (n *node) descend(segments []string, allowWildcard bool) (*node, error) {
 	lastIndex := len(segments) - 1
 
 	for i, segment := range segments {
@@ -85,6 +134,9 @@ func (n *node) add(method, pattern string, handler http.Handler, middlewares []M
 
 			// Validate wildcard position (must be last segment)
 			if strings.HasPrefix(paramName, prefixWildcard) {
+				if !allowWildcard {
+					return nil, fmt.Errorf("router wildcard %s is not allowed here", segment)
+				}
 				if i != lastIndex {
 					return nil, fmt.Errorf("router wildcard %s must be the last segment", segment)
 				}
@@ -95,11 +147,12 @@ func (n *node) add(method, pattern string, handler http.Handler, middlewares []M
 				n.paramNode.paramName = paramName
 				if len(parts) > 1 {
 					n.paramNode.regex = makeRegexp(parts[1])
+					n.paramNode.regexSrc = parts[1]
 				}
 			}
 			n = n.paramNode
 		} else {
-			// Add static path segment to routing tree
+			// Add static segment to routing tree
 			child, exists := n.children[segment]
 			if !exists {
 				child = newNode()
@@ -109,17 +162,56 @@ func (n *node) add(method, pattern string, handler http.Handler, middlewares []M
 		}
 	}
 
-	n.isEnd = true
-	n.methods[method] = handler
-	n.middlewares = append(n.middlewares, middlewares...)
 	return n, nil
 }
 
-// find traverses the routing tree to match URL segments and collect parameters
-// Returns matched node or nil if no match found
-func (n *node) find(method, url string) *node {
-	params := make(map[string]string)
-	segments := strings.Split(url, "/")
+// add registers a route handler for the given method and pattern
+// Returns error for invalid inputs or route conflicts
+func (n *node) add(method, pattern string, handler http.Handler, middlewares []Middleware) (*node, *route, error) {
+	if method == "" || pattern == "" || handler == nil {
+		return nil, nil, errors.New("mux handle error")
+	}
+	n, err := n.descend(strings.Split(pattern, "/"), true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n.isEnd = true
+	rt := &route{handler: handler, middlewares: append([]Middleware(nil), middlewares...)}
+	n.methods[method] = append(n.methods[method], rt)
+	return n, rt, nil
+}
+
+// addHost walks a dot-delimited host pattern (e.g. "api.{sub}.example.com")
+// through the host-trie and returns n's dedicated path-trie root for that
+// host. Wildcard segments are not allowed in host patterns. The host node
+// itself keeps using children/paramNode for further host-label descent
+// (e.g. a longer registered host like "api.example.com.uk"), so the
+// returned pathRoot must be a separate node or a host pattern that is a
+// label-extension of another would collide with it in the same trie.
+func (n *node) addHost(pattern string) (*node, error) {
+	n, err := n.descend(strings.Split(pattern, "."), false)
+	if err != nil {
+		return nil, err
+	}
+	n.isHostRoot = true
+	if n.pathRoot == nil {
+		n.pathRoot = newNode()
+	}
+	return n.pathRoot, nil
+}
+
+// find traverses the routing tree to match the request's URL segments,
+// evaluates predicates for the matching method, and collects parameters.
+// Returns the matched node or nil if no match found.
+func (n *node) find(r *http.Request) *node {
+	return n.findParams(r, make(map[string]string))
+}
+
+// findParams is like find but seeds the collected parameters with params,
+// allowing host params matched earlier to be merged with path params.
+func (n *node) findParams(r *http.Request, params map[string]string) *node {
+	segments := strings.Split(r.URL.Path, "/")
 	for i, segment := range segments {
 		if segment == "" {
 			continue
@@ -155,23 +247,168 @@ func (n *node) find(method, url string) *node {
 		return nil
 	}
 
-	if n.isEnd {
-		// Find method handler, fallback to wildcard if exists
-		handler := n.methods[method]
-		if handler == nil {
-			handler = n.methods[prefixWildcard]
+	if !n.isEnd {
+		return nil
+	}
+
+	// Find candidate routes for the method, falling back to wildcard if exists
+	routes := n.methods[r.Method]
+	if routes == nil {
+		routes = n.methods[prefixWildcard]
+	}
+	if routes == nil {
+		// Path matched but no route registered for this method: 405
+		n.params = params
+		n.handler = nil
+		return n
+	}
+
+	for _, rt := range routes {
+		extra, ok := rt.match(r)
+		if !ok {
+			continue
 		}
+		for k, v := range extra {
+			params[k] = v
+		}
+		handler := rt.handler
 		// Apply middleware chain in reverse order
-		for i := len(n.middlewares) - 1; i >= 0; i-- {
-			handler = n.middlewares[i](handler)
+		for i := len(rt.middlewares) - 1; i >= 0; i-- {
+			handler = rt.middlewares[i](handler)
 		}
 		n.params = params
 		n.handler = handler
 		return n
 	}
+
+	// Path and method matched but every route's predicates failed: 404
+	return nil
+}
+
+// findHost traverses the host-trie using dot-delimited host segments and
+// returns the matched host's dedicated path-trie root (see addHost) along
+// with any captured host parameters. Returns nil if no host matches.
+func (n *node) findHost(host string) (*node, map[string]string) {
+	params := make(map[string]string)
+	segments := strings.Split(host, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+
+		if child := n.children[segment]; child != nil {
+			n = child
+			continue
+		}
+
+		if n.paramNode != nil {
+			paramNode := n.paramNode
+			if paramNode.regex != nil && !paramNode.regex.MatchString(segment) {
+				return nil, nil
+			}
+			params[paramNode.paramName] = segment
+			n = paramNode
+			continue
+		}
+		return nil, nil
+	}
+	if !n.isHostRoot {
+		return nil, nil
+	}
+	return n.pathRoot, params
+}
+
+// segmentLabel renders a param or wildcard node as its original {name} or
+// {name:regex} pattern segment
+func segmentLabel(n *node) string {
+	label := n.paramName
+	if n.regexSrc != "" {
+		label += prefixRegexp + n.regexSrc
+	}
+	return prefixParam + label + suffixParam
+}
+
+// walk performs a depth-first traversal of the path-trie rooted at n,
+// invoking fn for every registered (method, pattern) pair. prefix is the
+// pattern reconstructed so far from ancestor segments.
+func (n *node) walk(prefix string, fn func(method, pattern string, handler http.Handler, middlewares []Middleware) error) error {
+	if n.isEnd {
+		pattern := prefix
+		if pattern == "" {
+			pattern = "/"
+		}
+		for method, routes := range n.methods {
+			for _, rt := range routes {
+				if err := fn(method, pattern, rt.handler, rt.middlewares); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for segment, child := range n.children {
+		if err := child.walk(prefix+"/"+segment, fn); err != nil {
+			return err
+		}
+	}
+	if n.paramNode != nil {
+		if err := n.paramNode.walk(prefix+"/"+segmentLabel(n.paramNode), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkHost performs a depth-first traversal of the host-trie rooted at n,
+// surfacing path routes registered under each host with node.walk against
+// that host's own pathRoot, reporting the pattern as "host/path" so
+// host-scoped routes aren't skipped by Walk. n's children/paramNode are
+// always host labels (see addHost), never path structure, so walkHost
+// keeps descending them after walking pathRoot: a host pattern that is a
+// label-extension of another (e.g. "example.com.uk" under "example.com")
+// is a distinct, deeper isHostRoot node in the same host-trie.
+func (n *node) walkHost(hostPrefix string, fn func(method, pattern string, handler http.Handler, middlewares []Middleware) error) error {
+	if hostPrefix != "" && n.isHostRoot && n.pathRoot != nil {
+		if err := n.pathRoot.walk(hostPrefix, fn); err != nil {
+			return err
+		}
+	}
+
+	for segment, child := range n.children {
+		next := segment
+		if hostPrefix != "" {
+			next = hostPrefix + "." + segment
+		}
+		if err := child.walkHost(next, fn); err != nil {
+			return err
+		}
+	}
+	if n.paramNode != nil {
+		label := segmentLabel(n.paramNode)
+		next := label
+		if hostPrefix != "" {
+			next = hostPrefix + "." + label
+		}
+		if err := n.paramNode.walkHost(next, fn); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// allowedMethods returns the sorted, deduped set of HTTP methods registered
+// directly on this node (the wildcard method "*" is never present here,
+// since a "*" registration always supplies a handler and so never reaches
+// the method-not-allowed path that calls this)
+func (n *node) allowedMethods() []string {
+	methods := make([]string, 0, len(n.methods))
+	for method := range n.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
 // withContext injects route parameters and current node into request context
 func (n *node) withContext(r *http.Request) *http.Request {
 	ctx := context.WithValue(r.Context(), keyRoute, n)